@@ -0,0 +1,112 @@
+package fwautomation
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataSourceFirewallGroup exposes a single group's membership and rules,
+// as reported by `show group`. It reuses the same parser and pooled SSH
+// client as the fwautomation_fwgroup resource.
+func dataSourceFirewallGroup() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFirewallGroupRead,
+		Schema: map[string]*schema.Schema{
+			"group_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"members": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     firewallGroupMemberResource(),
+			},
+			"rule": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     firewallGroupRuleLineResource(),
+			},
+		},
+	}
+}
+
+func firewallGroupMemberResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"hostname": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ip_address": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func firewallGroupRuleLineResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"protocol":    {Type: schema.TypeString, Computed: true},
+			"direction":   {Type: schema.TypeString, Computed: true},
+			"action":      {Type: schema.TypeString, Computed: true},
+			"port":        {Type: schema.TypeString, Computed: true},
+			"source":      {Type: schema.TypeString, Computed: true},
+			"destination": {Type: schema.TypeString, Computed: true},
+		},
+	}
+}
+
+func dataSourceFirewallGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ManagementConfig)
+	groupName := d.Get("group_name").(string)
+
+	group, err := readFirewallGroup(ctx, config.Pool, groupName)
+	if err != nil {
+		return diagFromSSHError(err)
+	}
+	if group == nil {
+		return diag.Errorf("group %q does not exist", groupName)
+	}
+
+	members := make([]interface{}, 0, len(group.Members))
+	for _, member := range group.Members {
+		members = append(members, map[string]interface{}{
+			"hostname":   member.Hostname,
+			"ip_address": member.IPAddress,
+		})
+	}
+	if err := d.Set("members", members); err != nil {
+		return diag.FromErr(err)
+	}
+
+	rules := make([]interface{}, 0, len(group.Rules))
+	for _, rule := range group.Rules {
+		rules = append(rules, map[string]interface{}{
+			"protocol":    rule.Protocol,
+			"direction":   rule.Direction,
+			"action":      rule.Action,
+			"port":        rule.Port,
+			"source":      rule.Source,
+			"destination": rule.Destination,
+		})
+	}
+	if err := d.Set("rule", rules); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("created_at", group.CreatedAt); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(group.Name)
+
+	return nil
+}
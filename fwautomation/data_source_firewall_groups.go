@@ -0,0 +1,167 @@
+package fwautomation
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dataSourceFirewallGroups lists every group on the device via
+// `list groups`, narrowed by an optional set of `filter` blocks.
+func dataSourceFirewallGroups() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFirewallGroupsRead,
+		Schema: map[string]*schema.Schema{
+			"filter": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"group_name", "hostname"}, false),
+						},
+						"values": {
+							Type:     schema.TypeList,
+							Required: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"regex": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"groups": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group_name": {Type: schema.TypeString, Computed: true},
+						"created_at": {Type: schema.TypeString, Computed: true},
+						"members": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     firewallGroupMemberResource(),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func listGroupsCommand() string {
+	return "list groups"
+}
+
+// firewallGroupFilter is the expanded form of one `filter` block.
+type firewallGroupFilter struct {
+	Name   string
+	Values []string
+	Regex  bool
+}
+
+func expandFirewallGroupFilters(raw []interface{}) []firewallGroupFilter {
+	filters := make([]firewallGroupFilter, 0, len(raw))
+	for _, r := range raw {
+		m := r.(map[string]interface{})
+		filters = append(filters, firewallGroupFilter{
+			Name:   m["name"].(string),
+			Values: toStringList(m["values"]),
+			Regex:  m["regex"].(bool),
+		})
+	}
+	return filters
+}
+
+// matchesFirewallGroupFilters reports whether g satisfies every filter
+// (AND across filters, OR across a single filter's values), the same
+// semantics as the filter blocks on hashicorp/aws's data sources.
+func matchesFirewallGroupFilters(g firewallGroupSummary, filters []firewallGroupFilter) bool {
+	for _, f := range filters {
+		if !matchesFirewallGroupFilter(g, f) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesFirewallGroupFilter(g firewallGroupSummary, f firewallGroupFilter) bool {
+	var candidates []string
+	switch f.Name {
+	case "group_name":
+		candidates = []string{g.Name}
+	case "hostname":
+		for _, member := range g.Members {
+			candidates = append(candidates, member.Hostname)
+		}
+	}
+
+	for _, candidate := range candidates {
+		for _, value := range f.Values {
+			if f.Regex {
+				if matched, err := regexp.MatchString(value, candidate); err == nil && matched {
+					return true
+				}
+				continue
+			}
+			if candidate == value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func dataSourceFirewallGroupsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ManagementConfig)
+
+	output, _, err := runSSHCommandCaptureOutput(ctx, config.Pool, listGroupsCommand())
+	if err != nil {
+		return diagFromSSHError(err)
+	}
+
+	summaries, err := parseListGroupsOutput(output)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	filters := expandFirewallGroupFilters(d.Get("filter").([]interface{}))
+
+	groups := make([]interface{}, 0, len(summaries))
+	for _, g := range summaries {
+		if !matchesFirewallGroupFilters(g, filters) {
+			continue
+		}
+
+		members := make([]interface{}, 0, len(g.Members))
+		for _, member := range g.Members {
+			members = append(members, map[string]interface{}{
+				"hostname":   member.Hostname,
+				"ip_address": member.IPAddress,
+			})
+		}
+
+		groups = append(groups, map[string]interface{}{
+			"group_name": g.Name,
+			"created_at": g.CreatedAt,
+			"members":    members,
+		})
+	}
+
+	if err := d.Set("groups", groups); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(uuid.NewString())
+
+	return nil
+}
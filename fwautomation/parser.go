@@ -0,0 +1,149 @@
+package fwautomation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// parsedFirewallMember is a single host entry in a group's membership table.
+type parsedFirewallMember struct {
+	Hostname  string
+	IPAddress string
+}
+
+// parsedFirewallRule is a single rule line in a group's rule table.
+type parsedFirewallRule struct {
+	Protocol    string
+	Direction   string
+	Action      string
+	Port        string
+	Source      string
+	Destination string
+}
+
+// parsedFirewallGroup is the structured form of `show group` output.
+type parsedFirewallGroup struct {
+	Name      string
+	CreatedAt string
+	Members   []parsedFirewallMember
+	Rules     []parsedFirewallRule
+}
+
+var groupNotFoundRe = regexp.MustCompile(`(?i)no such group|group not found|does not exist`)
+var groupHeaderRe = regexp.MustCompile(`^Group:\s*(\S+)`)
+var createdAtRe = regexp.MustCompile(`^Created:\s*(\S+)`)
+var membersHeaderRe = regexp.MustCompile(`^Members:`)
+var rulesHeaderRe = regexp.MustCompile(`^Rules:`)
+var memberRowRe = regexp.MustCompile(`^\s*(\S+)\s+(\d+\.\d+\.\d+\.\d+)\s*$`)
+var ruleRowRe = regexp.MustCompile(`^\s*(tcp|udp|icmp|all)\s+(ingress|egress)\s+(allow|deny)\s+(\S+)\s+(\S+)\s+(\S+)\s*$`)
+
+// parseShowGroupOutput tolerantly parses the stdout of a `show group`
+// command into a parsedFirewallGroup. It returns (nil, nil) when the
+// device reports the group doesn't exist, so callers can distinguish
+// "absent" from "malformed" and clear the resource's ID accordingly.
+func parseShowGroupOutput(output string) (*parsedFirewallGroup, error) {
+	if groupNotFoundRe.MatchString(output) {
+		return nil, nil
+	}
+
+	group := &parsedFirewallGroup{}
+	inMembers := false
+	inRules := false
+
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimRight(line, "\r")
+
+		switch {
+		case groupHeaderRe.MatchString(trimmed):
+			group.Name = groupHeaderRe.FindStringSubmatch(trimmed)[1]
+			inMembers, inRules = false, false
+		case createdAtRe.MatchString(trimmed):
+			group.CreatedAt = createdAtRe.FindStringSubmatch(trimmed)[1]
+		case membersHeaderRe.MatchString(trimmed):
+			inMembers, inRules = true, false
+		case rulesHeaderRe.MatchString(trimmed):
+			inMembers, inRules = false, true
+		case inMembers:
+			if m := memberRowRe.FindStringSubmatch(trimmed); m != nil {
+				group.Members = append(group.Members, parsedFirewallMember{
+					Hostname:  m[1],
+					IPAddress: m[2],
+				})
+			}
+		case inRules:
+			if m := ruleRowRe.FindStringSubmatch(trimmed); m != nil {
+				group.Rules = append(group.Rules, parsedFirewallRule{
+					Protocol:    m[1],
+					Direction:   m[2],
+					Action:      m[3],
+					Port:        normalizeTableField(m[4]),
+					Source:      normalizeTableField(m[5]),
+					Destination: normalizeTableField(m[6]),
+				})
+			}
+		}
+	}
+
+	if group.Name == "" {
+		return nil, fmt.Errorf("unrecognized `show group` output: missing \"Group:\" header")
+	}
+
+	return group, nil
+}
+
+// normalizeTableField maps the device's placeholder for an empty table
+// cell ("-") to an empty string.
+func normalizeTableField(field string) string {
+	if field == "-" {
+		return ""
+	}
+	return field
+}
+
+// firewallGroupSummary is a single group and its members, as reported by
+// `list groups`.
+type firewallGroupSummary struct {
+	Name      string
+	CreatedAt string
+	Members   []parsedFirewallMember
+}
+
+var listGroupsHeaderRe = regexp.MustCompile(`(?i)^\s*GROUP\s+HOSTNAME`)
+var listGroupsRowRe = regexp.MustCompile(`^\s*(\S+)\s+(\S+)\s+(\d+\.\d+\.\d+\.\d+)\s+(\S+)\s*$`)
+
+// parseListGroupsOutput tolerantly parses the stdout of a `list groups`
+// command, which reports one row per group member, into a summary per
+// group preserving the device's ordering.
+func parseListGroupsOutput(output string) ([]firewallGroupSummary, error) {
+	var summaries []firewallGroupSummary
+	index := make(map[string]int)
+
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimRight(line, "\r")
+		if trimmed == "" || listGroupsHeaderRe.MatchString(trimmed) {
+			continue
+		}
+
+		m := listGroupsRowRe.FindStringSubmatch(trimmed)
+		if m == nil {
+			continue
+		}
+
+		name, hostname, ip, createdAt := m[1], m[2], m[3], m[4]
+
+		i, ok := index[name]
+		if !ok {
+			i = len(summaries)
+			index[name] = i
+			summaries = append(summaries, firewallGroupSummary{Name: name, CreatedAt: createdAt})
+		}
+
+		summaries[i].Members = append(summaries[i].Members, parsedFirewallMember{
+			Hostname:  hostname,
+			IPAddress: ip,
+		})
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,116 @@
+package fwautomation
+
+import "testing"
+
+func TestParseShowGroupOutput_MembersAndRules(t *testing.T) {
+	output := `Group: WEBSERVERS
+Created: 2023-01-15T10:00:00Z
+Members:
+  HOSTNAME             IP
+  web1.example.com     10.0.1.5
+  web2.example.com     10.0.1.6
+Rules:
+  PROTO  DIRECTION  ACTION  PORT  SOURCE       DESTINATION
+  tcp    ingress    allow   443   0.0.0.0/0    10.0.1.0/24
+  tcp    ingress    allow   80    0.0.0.0/0    10.0.1.0/24
+  icmp   egress     deny    -     10.0.1.0/24  -
+`
+
+	group, err := parseShowGroupOutput(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if group.Name != "WEBSERVERS" {
+		t.Errorf("expected name WEBSERVERS, got %q", group.Name)
+	}
+	if group.CreatedAt != "2023-01-15T10:00:00Z" {
+		t.Errorf("expected created_at to be parsed, got %q", group.CreatedAt)
+	}
+
+	if len(group.Members) != 2 {
+		t.Fatalf("expected 2 members, got %d", len(group.Members))
+	}
+	if group.Members[0].Hostname != "web1.example.com" || group.Members[0].IPAddress != "10.0.1.5" {
+		t.Errorf("unexpected first member: %+v", group.Members[0])
+	}
+
+	if len(group.Rules) != 3 {
+		t.Fatalf("expected 3 rules, got %d", len(group.Rules))
+	}
+	last := group.Rules[2]
+	if last.Protocol != "icmp" || last.Direction != "egress" || last.Action != "deny" {
+		t.Errorf("unexpected last rule: %+v", last)
+	}
+	if last.Port != "" || last.Destination != "" {
+		t.Errorf("expected placeholder fields to normalize to empty string, got port=%q destination=%q", last.Port, last.Destination)
+	}
+}
+
+func TestParseShowGroupOutput_NoMembers(t *testing.T) {
+	output := `Group: EMPTYGROUP
+Created: 2023-01-15T10:00:00Z
+Members:
+Rules:
+`
+
+	group, err := parseShowGroupOutput(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(group.Members) != 0 || len(group.Rules) != 0 {
+		t.Errorf("expected no members or rules, got %+v", group)
+	}
+}
+
+func TestParseShowGroupOutput_GroupNotFound(t *testing.T) {
+	output := "Error: no such group WEBSERVERS\n"
+
+	group, err := parseShowGroupOutput(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if group != nil {
+		t.Errorf("expected nil group for a not-found response, got %+v", group)
+	}
+}
+
+func TestParseShowGroupOutput_Malformed(t *testing.T) {
+	_, err := parseShowGroupOutput("garbage that isn't a known format\n")
+	if err == nil {
+		t.Fatal("expected an error for unrecognized output")
+	}
+}
+
+func TestParseListGroupsOutput(t *testing.T) {
+	output := `GROUP        HOSTNAME             IP           CREATED
+WEBSERVERS   web1.example.com    10.0.1.5     2023-01-15T10:00:00Z
+WEBSERVERS   web2.example.com    10.0.1.6     2023-01-15T10:00:00Z
+DBSERVERS    db1.example.com     10.0.2.5     2023-02-01T08:00:00Z
+`
+
+	summaries, err := parseListGroupsOutput(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 group summaries, got %d", len(summaries))
+	}
+
+	web := summaries[0]
+	if web.Name != "WEBSERVERS" || web.CreatedAt != "2023-01-15T10:00:00Z" {
+		t.Errorf("unexpected first summary: %+v", web)
+	}
+	if len(web.Members) != 2 {
+		t.Fatalf("expected 2 members for WEBSERVERS, got %d", len(web.Members))
+	}
+	if web.Members[1].Hostname != "web2.example.com" || web.Members[1].IPAddress != "10.0.1.6" {
+		t.Errorf("unexpected second member: %+v", web.Members[1])
+	}
+
+	db := summaries[1]
+	if db.Name != "DBSERVERS" || len(db.Members) != 1 {
+		t.Errorf("unexpected second summary: %+v", db)
+	}
+}
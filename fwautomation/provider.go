@@ -2,15 +2,28 @@ package fwautomation
 
 import (
 	"context"
+	"os"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/crypto/ssh"
 )
 
 type ManagementConfig struct {
 	Server                string
 	Domain                string
 	AuthenticationKeyPath string // Make sure this is correctly added to the struct
+
+	SSHUser               string
+	SSHAgent              bool
+	Password              string
+	Passphrase            string
+	KnownHostsPath        string
+	HostKeyFingerprint    string
+	HostKeyAlgorithms     []string
+	InsecureIgnoreHostKey bool
+
+	Pool *sshClientPool
 }
 
 // Provider -
@@ -29,14 +42,62 @@ func Provider() *schema.Provider {
 			},
 			"authentication_key_path": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("FWGROUPS_AUTH_KEY_PATH", nil),
 			},
+			"ssh_user": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FWGROUPS_SSH_USER", "automate"),
+			},
+			"ssh_agent": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				DefaultFunc: func() (interface{}, error) {
+					return os.Getenv("SSH_AUTH_SOCK") != "", nil
+				},
+			},
+			"password": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("FWGROUPS_SSH_PASSWORD", nil),
+			},
+			"passphrase": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("FWGROUPS_SSH_KEY_PASSPHRASE", nil),
+			},
+			"known_hosts_path": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FWGROUPS_KNOWN_HOSTS_PATH", nil),
+			},
+			"host_key_fingerprint": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A pinned host public key, in authorized_keys format (e.g. \"ssh-ed25519 AAAA...\"), used instead of known_hosts_path.",
+			},
+			"host_key_algorithms": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"insecure_ignore_host_key": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Skip host key verification entirely. Only safe for lab use; do not set in production.",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"fwautomation_fwgroup": resourceFirewallGroup(),
 		},
-		DataSourcesMap:       map[string]*schema.Resource{},
+		DataSourcesMap: map[string]*schema.Resource{
+			"fwautomation_fwgroup":  dataSourceFirewallGroup(),
+			"fwautomation_fwgroups": dataSourceFirewallGroups(),
+		},
 		ConfigureContextFunc: providerConfigure,
 	}
 }
@@ -44,16 +105,28 @@ func Provider() *schema.Provider {
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
-	server := d.Get("management_server").(string)
-	domain := d.Get("domain").(string)
-	authKeyPath := d.Get("authentication_key_path").(string)
-
-	// Return a configuration object, not an SSH client
 	config := &ManagementConfig{
-		Server:                server,
-		Domain:                domain,
-		AuthenticationKeyPath: authKeyPath,
+		Server:                d.Get("management_server").(string),
+		Domain:                d.Get("domain").(string),
+		AuthenticationKeyPath: d.Get("authentication_key_path").(string),
+		SSHUser:               d.Get("ssh_user").(string),
+		SSHAgent:              d.Get("ssh_agent").(bool),
+		Password:              d.Get("password").(string),
+		Passphrase:            d.Get("passphrase").(string),
+		KnownHostsPath:        d.Get("known_hosts_path").(string),
+		HostKeyFingerprint:    d.Get("host_key_fingerprint").(string),
+		InsecureIgnoreHostKey: d.Get("insecure_ignore_host_key").(bool),
 	}
+	for _, alg := range d.Get("host_key_algorithms").([]interface{}) {
+		config.HostKeyAlgorithms = append(config.HostKeyAlgorithms, alg.(string))
+	}
+
+	// The pool dials lazily on first use so providerConfigure never blocks
+	// on the network, and shares one connection across every resource and
+	// data source operation in a plan.
+	config.Pool = newSSHClientPool(func(dialCtx context.Context) (*ssh.Client, error) {
+		return dialManagementServer(dialCtx, config)
+	})
 
 	return config, diags
 }
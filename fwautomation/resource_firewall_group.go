@@ -3,23 +3,37 @@ package fwautomation
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"io/ioutil" // Use ioutil for reading files
+	"hash/crc32"
+	"net"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"golang.org/x/crypto/ssh"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+var validProtocols = []string{"tcp", "udp", "icmp", "all"}
+var validDirections = []string{"ingress", "egress"}
+var validActions = []string{"allow", "deny"}
+
 func resourceFirewallGroup() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFirewallGroupCreate,
 		ReadContext:   resourceFirewallGroupRead,
+		UpdateContext: resourceFirewallGroupUpdate,
 		DeleteContext: resourceFirewallGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceFirewallGroupImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"group_name": {
 				Type:     schema.TypeString,
@@ -36,7 +50,6 @@ func resourceFirewallGroup() *schema.Resource {
 			"hostname": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
 					v := val.(string)
 					if !regexp.MustCompile(`^[a-z\.-]+$`).MatchString(v) {
@@ -48,7 +61,6 @@ func resourceFirewallGroup() *schema.Resource {
 			"ip_address": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
 					v := val.(string)
 					if !regexp.MustCompile(`^\d+\.\d+\.\d+\.\d+$`).MatchString(v) {
@@ -57,25 +69,184 @@ func resourceFirewallGroup() *schema.Resource {
 					return warns, errs
 				},
 			},
+			"rule": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     resourceFirewallRule(),
+				Set:      resourceFirewallRuleHash,
+			},
 		},
 		SchemaVersion: 1, // Set the schema version to 1
 	}
 }
 
-// Adding comments for better visibility
-func resourceFirewallGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	config := m.(*ManagementConfig)
-	var diags diag.Diagnostics
+// resourceFirewallRule describes a single allow/deny rule nested under a
+// fwautomation_fwgroup. Each rule block is rendered into one or more
+// `modify group ... proto=... port=... action=...` SSH commands.
+func resourceFirewallRule() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"protocol": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(validProtocols, false),
+			},
+			"ports": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validatePortOrRange,
+				},
+			},
+			"direction": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(validDirections, false),
+			},
+			"action": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(validActions, false),
+			},
+			"source_ranges": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateCIDR,
+				},
+			},
+			"destination_ranges": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateCIDR,
+				},
+			},
+		},
+	}
+}
 
-	client, err := setupSSHConnection(config)
-	if err != nil {
-		return diag.FromErr(err)
+func validatePortOrRange(val interface{}, key string) (warns []string, errs []error) {
+	v := val.(string)
+	if !regexp.MustCompile(`^\d+(-\d+)?$`).MatchString(v) {
+		errs = append(errs, fmt.Errorf("%q must be a port or port range, e.g. \"443\" or \"8000-8100\": %s", key, v))
+	}
+	return warns, errs
+}
+
+func validateCIDR(val interface{}, key string) (warns []string, errs []error) {
+	v := val.(string)
+	if _, _, err := net.ParseCIDR(v); err != nil {
+		errs = append(errs, fmt.Errorf("%q must be a valid CIDR, e.g. \"10.0.0.0/24\": %s", key, err))
+	}
+	return warns, errs
+}
+
+// resourceFirewallRuleHash computes the TypeSet hash for a rule block. Ports
+// and CIDRs are sorted/canonicalized first so that reordering them in
+// configuration doesn't change the hash and thrash Terraform's plan.
+func resourceFirewallRuleHash(v interface{}) int {
+	m := v.(map[string]interface{})
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s-", m["protocol"].(string))
+	fmt.Fprintf(&buf, "%s-", m["direction"].(string))
+	fmt.Fprintf(&buf, "%s-", m["action"].(string))
+
+	for _, p := range sortPorts(toStringList(m["ports"])) {
+		fmt.Fprintf(&buf, "%s-", p)
+	}
+	for _, c := range sortStrings(canonicalizeCIDRs(toStringList(m["source_ranges"]))) {
+		fmt.Fprintf(&buf, "%s-", c)
 	}
-	defer client.Close()
+	for _, c := range sortStrings(canonicalizeCIDRs(toStringList(m["destination_ranges"]))) {
+		fmt.Fprintf(&buf, "%s-", c)
+	}
+
+	return hashcodeString(buf.String())
+}
+
+// hashcodeString hashes s to a non-negative int, the same way the
+// Terraform SDK's own (internal, unimportable) hashcode helper does: CRC32
+// folded into the positive half of an int, with MinInt reserved to avoid
+// overflow on negation.
+func hashcodeString(s string) int {
+	v := int(crc32.ChecksumIEEE([]byte(s)))
+	if v >= 0 {
+		return v
+	}
+	if -v >= 0 {
+		return -v
+	}
+	return 0
+}
+
+func toStringList(raw interface{}) []string {
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(list))
+	for _, v := range list {
+		out = append(out, v.(string))
+	}
+	return out
+}
 
-	err = runResourceFirewallGroupsTask(client, d, "add")
+func sortStrings(in []string) []string {
+	out := append([]string(nil), in...)
+	sort.Strings(out)
+	return out
+}
+
+// sortPorts sorts port/port-range strings numerically by their starting
+// port so that ["443", "22", "8000-8100"] and ["22", "8000-8100", "443"]
+// produce the same, stable ordering.
+func sortPorts(in []string) []string {
+	out := append([]string(nil), in...)
+	sort.Slice(out, func(i, j int) bool {
+		si, sj := portRangeStart(out[i]), portRangeStart(out[j])
+		if si != sj {
+			return si < sj
+		}
+		return out[i] < out[j]
+	})
+	return out
+}
+
+func portRangeStart(portRange string) int {
+	start := strings.SplitN(portRange, "-", 2)[0]
+	n, err := strconv.Atoi(start)
 	if err != nil {
-		return diag.FromErr(err)
+		return 0
+	}
+	return n
+}
+
+// canonicalizeCIDRs normalizes each CIDR to its masked network form (e.g.
+// "10.0.0.5/24" becomes "10.0.0.0/24") so equivalent CIDRs hash the same.
+func canonicalizeCIDRs(in []string) []string {
+	out := make([]string, 0, len(in))
+	for _, c := range in {
+		_, network, err := net.ParseCIDR(c)
+		if err != nil {
+			out = append(out, c)
+			continue
+		}
+		out = append(out, network.String())
+	}
+	return out
+}
+
+func resourceFirewallGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ManagementConfig)
+	var diags diag.Diagnostics
+
+	if err := runResourceFirewallGroupsTask(ctx, config.Pool, d, "add"); err != nil {
+		return diagFromSSHError(err)
 	}
 
 	d.SetId(uuid.NewString()) // Correctly setting the ID after successful creation
@@ -86,72 +257,290 @@ func resourceFirewallGroupRead(ctx context.Context, d *schema.ResourceData, m in
 	config := m.(*ManagementConfig)
 	var diags diag.Diagnostics
 
-	client, err := setupSSHConnection(config)
+	groupName := d.Get("group_name").(string)
+
+	group, err := readFirewallGroup(ctx, config.Pool, groupName)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromSSHError(err)
+	}
+	if group == nil {
+		// The group no longer exists on the device; let Terraform plan a recreate.
+		d.SetId("")
+		return diags
 	}
-	defer client.Close()
 
-	err = runResourceFirewallGroupsTask(client, d, "read")
-	if err != nil {
+	if err := setResourceDataFromParsedGroup(d, group); err != nil {
 		return diag.FromErr(err)
 	}
 
 	return diags
 }
 
-func resourceFirewallGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+// readFirewallGroup runs `show group` for groupName and parses the result.
+// It returns (nil, nil) when the device reports the group doesn't exist.
+func readFirewallGroup(ctx context.Context, pool *sshClientPool, groupName string) (*parsedFirewallGroup, error) {
+	output, _, err := runSSHCommandCaptureOutput(ctx, pool, showGroupCommand(groupName))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseShowGroupOutput(output)
+}
+
+func showGroupCommand(groupName string) string {
+	return fmt.Sprintf("show group group=%s", groupName)
+}
+
+// setResourceDataFromParsedGroup reconciles a parsed `show group` response
+// against the resource's schema. The configured hostname/ip_address are
+// kept as the resource's own identity; if they're no longer present in the
+// group's membership the resource has drifted out of existence.
+func setResourceDataFromParsedGroup(d *schema.ResourceData, group *parsedFirewallGroup) error {
+	hostname := d.Get("hostname").(string)
+	ipAddress := d.Get("ip_address").(string)
+
+	member := false
+	for _, m := range group.Members {
+		if m.Hostname == hostname && m.IPAddress == ipAddress {
+			member = true
+			break
+		}
+	}
+	if !member {
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("group_name", group.Name); err != nil {
+		return err
+	}
+	if err := d.Set("hostname", hostname); err != nil {
+		return err
+	}
+	if err := d.Set("ip_address", ipAddress); err != nil {
+		return err
+	}
+
+	rules := make([]interface{}, 0, len(group.Rules))
+	for _, r := range group.Rules {
+		rule := map[string]interface{}{
+			"protocol":  r.Protocol,
+			"direction": r.Direction,
+			"action":    r.Action,
+		}
+		if r.Port != "" {
+			rule["ports"] = []interface{}{r.Port}
+		}
+		if r.Source != "" {
+			rule["source_ranges"] = []interface{}{r.Source}
+		}
+		if r.Destination != "" {
+			rule["destination_ranges"] = []interface{}{r.Destination}
+		}
+		rules = append(rules, rule)
+	}
+	return d.Set("rule", rules)
+}
+
+// resourceFirewallGroupUpdate applies a membership change in place,
+// without destroying and recreating the resource: it removes the old
+// hostname/ip_address tuple and then adds the new one, batched into a
+// single SSH session when the device accepts that (see
+// runBatchedSSHCommands). If either step fails, it rolls back by
+// re-adding the old tuple and reports both the failure and the rollback
+// outcome.
+func resourceFirewallGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	config := m.(*ManagementConfig)
 	var diags diag.Diagnostics
 
-	client, err := setupSSHConnection(config)
-	if err != nil {
-		return diag.FromErr(err)
+	groupName := d.Get("group_name").(string)
+
+	if d.HasChange("hostname") || d.HasChange("ip_address") {
+		oldHostnameRaw, newHostnameRaw := d.GetChange("hostname")
+		oldIPAddressRaw, newIPAddressRaw := d.GetChange("ip_address")
+		oldHostname, newHostname := oldHostnameRaw.(string), newHostnameRaw.(string)
+		oldIPAddress, newIPAddress := oldIPAddressRaw.(string), newIPAddressRaw.(string)
+
+		removeCmd := fmt.Sprintf("modify group group=%s hostname=%s ip=%s method=remove", groupName, oldHostname, oldIPAddress)
+		addCmd := fmt.Sprintf("modify group group=%s hostname=%s ip=%s method=add", groupName, newHostname, newIPAddress)
+
+		if err := runBatchedSSHCommands(ctx, config.Pool, []string{removeCmd, addCmd}); err != nil {
+			rollbackCmd := fmt.Sprintf("modify group group=%s hostname=%s ip=%s method=add", groupName, oldHostname, oldIPAddress)
+			if rollbackErr := runSSHCommand(ctx, config.Pool, rollbackCmd); rollbackErr != nil {
+				return diag.Diagnostics{{
+					Severity: diag.Error,
+					Summary:  "failed to update firewall group membership, and rollback to the previous member also failed",
+					Detail:   fmt.Sprintf("update failed: %s; rollback command %q also failed: %s", err, rollbackCmd, rollbackErr),
+				}}
+			}
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  "failed to update firewall group membership; rolled back to the previous member",
+				Detail:   fmt.Sprintf("update failed: %s; successfully rolled back with %q", err, rollbackCmd),
+			}}
+		}
+	}
+
+	if d.HasChange("rule") {
+		if err := updateFirewallGroupRules(ctx, config.Pool, groupName, d); err != nil {
+			return diagFromSSHError(err)
+		}
+	}
+
+	return diags
+}
+
+// updateFirewallGroupRules diffs the old and new `rule` sets and sends
+// only the commands needed to reach the new set: a `method=remove` for
+// every rule dropped from the configuration and a `method=add` for every
+// rule added to it. Rules present in both sets are left alone.
+func updateFirewallGroupRules(ctx context.Context, pool *sshClientPool, groupName string, d *schema.ResourceData) error {
+	oldRaw, newRaw := d.GetChange("rule")
+	oldRules, newRules := oldRaw.(*schema.Set), newRaw.(*schema.Set)
+
+	removed := oldRules.Difference(newRules).List()
+	added := newRules.Difference(oldRules).List()
+
+	var cmds []string
+	cmds = append(cmds, renderRuleCommands(groupName, removed, "remove")...)
+	cmds = append(cmds, renderRuleCommands(groupName, added, "add")...)
+
+	if len(cmds) == 0 {
+		return nil
 	}
-	defer client.Close()
 
-	err = runResourceFirewallGroupsTask(client, d, "remove")
+	return runBatchedSSHCommands(ctx, pool, cmds)
+}
+
+// resourceFirewallGroupImport accepts either "group_name" or
+// "group_name/hostname" as the import ID, letting a firewall group that
+// was provisioned outside Terraform be imported for a single member host.
+func resourceFirewallGroupImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	config := m.(*ManagementConfig)
+
+	parts := strings.SplitN(d.Id(), "/", 2)
+	groupName := parts[0]
+
+	group, err := readFirewallGroup(ctx, config.Pool, groupName)
 	if err != nil {
-		return diag.FromErr(err)
+		return nil, err
+	}
+	if group == nil {
+		return nil, fmt.Errorf("group %q does not exist", groupName)
+	}
+
+	var member *parsedFirewallMember
+	if len(parts) == 2 {
+		hostname := parts[1]
+		for i, m := range group.Members {
+			if m.Hostname == hostname {
+				member = &group.Members[i]
+				break
+			}
+		}
+		if member == nil {
+			return nil, fmt.Errorf("hostname %q is not a member of group %q", hostname, groupName)
+		}
+	} else {
+		if len(group.Members) != 1 {
+			return nil, fmt.Errorf("group %q has %d members; import as \"group_name/hostname\" to select one", groupName, len(group.Members))
+		}
+		member = &group.Members[0]
+	}
+
+	if err := d.Set("group_name", group.Name); err != nil {
+		return nil, err
+	}
+	if err := d.Set("hostname", member.Hostname); err != nil {
+		return nil, err
+	}
+	if err := d.Set("ip_address", member.IPAddress); err != nil {
+		return nil, err
+	}
+	d.SetId(uuid.NewString())
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceFirewallGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ManagementConfig)
+	var diags diag.Diagnostics
+
+	if err := runResourceFirewallGroupsTask(ctx, config.Pool, d, "remove"); err != nil {
+		return diagFromSSHError(err)
 	}
 
 	d.SetId("") // Correctly clearing the ID upon successful deletion
 	return diags
 }
 
-func setupSSHConnection(config *ManagementConfig) (*ssh.Client, error) {
-	key, err := ioutil.ReadFile(config.AuthenticationKeyPath)
+func runResourceFirewallGroupsTask(ctx context.Context, pool *sshClientPool, d *schema.ResourceData, method string) error {
+	cmds, err := generateCommands(d, method)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load private key: %s", err)
+		return fmt.Errorf("error generating commands: %s", err)
 	}
 
-	signer, err := ssh.ParsePrivateKey(key)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse private key: %s", err)
+	for _, cmd := range cmds {
+		if err := runSSHCommand(ctx, pool, cmd); err != nil {
+			return err
+		}
 	}
 
-	sshConfig := &ssh.ClientConfig{
-		User: "automate",
-		Auth: []ssh.AuthMethod{
-			ssh.PublicKeys(signer),
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         5 * time.Second,
+	return nil
+}
+
+func runSSHCommand(ctx context.Context, pool *sshClientPool, cmd string) error {
+	_, _, err := runSSHCommandCaptureOutput(ctx, pool, cmd)
+	return err
+}
+
+// runBatchedSSHCommands runs cmds over a single SSH session when there's
+// more than one, joining them the way the device accepts batched input.
+// If the device rejects the batched form, it falls back to running each
+// command over its own session so a device that doesn't support batching
+// still gets a correct, if less atomic, result.
+func runBatchedSSHCommands(ctx context.Context, pool *sshClientPool, cmds []string) error {
+	if len(cmds) == 1 {
+		return runSSHCommand(ctx, pool, cmds[0])
 	}
 
-	// Ensure the server address includes a port
-	serverAddress := config.Server
-	if !strings.Contains(serverAddress, ":") {
-		serverAddress = fmt.Sprintf("%s:22", serverAddress) // Append port if not present
+	batched := strings.Join(cmds, "; ")
+	if err := runSSHCommand(ctx, pool, batched); err == nil {
+		return nil
 	}
 
-	return ssh.Dial("tcp", serverAddress, sshConfig)
+	for _, cmd := range cmds {
+		if err := runSSHCommand(ctx, pool, cmd); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func runResourceFirewallGroupsTask(client *ssh.Client, d *schema.ResourceData, method string) error {
-	session, err := client.NewSession()
+// runSSHCommandCaptureOutput runs cmd over a new session on the pool's
+// shared SSH connection, logging its start/end and duration, and returns
+// its stdout and stderr. If ctx is cancelled while the command is
+// running, the remote process is killed and the session torn down.
+func runSSHCommandCaptureOutput(ctx context.Context, pool *sshClientPool, cmd string) (string, string, error) {
+	sanitized := sanitizeCommandForLogging(cmd)
+	tflog.Debug(ctx, "executing SSH command", map[string]interface{}{"command": sanitized})
+	start := time.Now()
+
+	stdout, stderr, err := runSSHCommandCaptureOutputUnlogged(ctx, pool, cmd)
+
+	tflog.Debug(ctx, "SSH command finished", map[string]interface{}{
+		"command":     sanitized,
+		"duration_ms": time.Since(start).Milliseconds(),
+		"error":       err != nil,
+	})
+
+	return stdout, stderr, err
+}
+
+func runSSHCommandCaptureOutputUnlogged(ctx context.Context, pool *sshClientPool, cmd string) (string, string, error) {
+	session, err := pool.NewSession(ctx)
 	if err != nil {
-		return fmt.Errorf("error creating SSH session: %s", err)
+		return "", "", fmt.Errorf("error creating SSH session: %s", err)
 	}
 	defer session.Close()
 
@@ -159,32 +548,159 @@ func runResourceFirewallGroupsTask(client *ssh.Client, d *schema.ResourceData, m
 	session.Stdout = &stdout
 	session.Stderr = &stderr
 
-	cmd, err := generateCommand(d, method)
-	if err != nil {
-		return fmt.Errorf("error executing command: %s", err)
-	}
-
 	if err := session.Start(cmd); err != nil {
-		return fmt.Errorf("error starting command: %s, stderr: %s, stdout: %s", err, stderr.String(), stdout.String())
+		return "", stderr.String(), &sshCommandError{cmd: cmd, stderr: stderr.String(), err: fmt.Errorf("error starting command: %s", err)}
 	}
 
 	if err := session.Wait(); err != nil {
-		return fmt.Errorf("error waiting for command completion: %s, stderr: %s, stdout: %s", err, stderr.String(), stdout.String())
+		return "", stderr.String(), &sshCommandError{cmd: cmd, stderr: stderr.String(), err: err}
 	}
 
-	return nil
+	return stdout.String(), stderr.String(), nil
+}
+
+// sanitizeCommandForLogging redacts any password= value before a command
+// is written to structured logs.
+func sanitizeCommandForLogging(cmd string) string {
+	return regexp.MustCompile(`password=\S+`).ReplaceAllString(cmd, "password=***")
 }
 
-func generateCommand(d *schema.ResourceData, method string) (string, error) {
+// sshCommandError is returned when the remote device rejects a command.
+// It carries the command and stderr so diagFromSSHError can surface a
+// diagnostic pointing at the likely offending attribute.
+type sshCommandError struct {
+	cmd    string
+	stderr string
+	err    error
+}
+
+func (e *sshCommandError) Error() string {
+	return fmt.Sprintf("error waiting for command completion: %s, stderr: %s", e.err, e.stderr)
+}
+
+func (e *sshCommandError) Unwrap() error {
+	return e.err
+}
+
+// diagFromSSHError converts an error from the SSH command path into rich
+// diagnostics: an Error diagnostic with an AttributePath pointing at
+// whichever of group_name/hostname/ip_address the remote command's
+// stderr appears to reject, plus a separate Warning diagnostic
+// summarizing that stderr.
+func diagFromSSHError(err error) diag.Diagnostics {
+	var cmdErr *sshCommandError
+	if !errors.As(err, &cmdErr) {
+		return diag.FromErr(err)
+	}
+
+	diags := diag.Diagnostics{
+		{
+			Severity:      diag.Error,
+			Summary:       "firewall device rejected command",
+			Detail:        cmdErr.err.Error(),
+			AttributePath: attributePathForStderr(cmdErr.stderr),
+		},
+	}
+
+	if cmdErr.stderr != "" {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "remote command produced stderr output",
+			Detail:   cmdErr.stderr,
+		})
+	}
+
+	return diags
+}
+
+// attributePathForStderr guesses which resource attribute a rejected
+// command's stderr is complaining about, so the diagnostic can point the
+// user at the right line of their configuration.
+func attributePathForStderr(stderr string) cty.Path {
+	lower := strings.ToLower(stderr)
+	switch {
+	case strings.Contains(lower, "hostname"):
+		return cty.GetAttrPath("hostname")
+	case strings.Contains(lower, "ip"):
+		return cty.GetAttrPath("ip_address")
+	case strings.Contains(lower, "group"):
+		return cty.GetAttrPath("group_name")
+	default:
+		return nil
+	}
+}
+
+// generateCommands renders the resource's configuration into the ordered
+// list of SSH commands needed to reach the requested method. A group with
+// nested rule blocks produces one base membership command plus one
+// `modify group ... proto=... port=... action=...` command per port of
+// each rule (a single command for a rule with no ports).
+func generateCommands(d *schema.ResourceData, method string) ([]string, error) {
 	groupName := d.Get("group_name").(string)
 	hostname := d.Get("hostname").(string)
 	ipAddress := d.Get("ip_address").(string)
 
-	if method == "add" || method == "remove" {
-		return fmt.Sprintf("modify group group=%s hostname=%s ip=%s method=%s", groupName, hostname, ipAddress, method), nil
-	} else if method == "read" {
-		return fmt.Sprintf("show group group=%s", groupName), nil
-	} else {
-		return "", fmt.Errorf("method not supported: %s", method)
+	switch method {
+	case "add", "remove":
+		cmds := []string{
+			fmt.Sprintf("modify group group=%s hostname=%s ip=%s method=%s", groupName, hostname, ipAddress, method),
+		}
+		cmds = append(cmds, generateRuleCommands(d, groupName, method)...)
+		return cmds, nil
+	case "read":
+		return []string{showGroupCommand(groupName)}, nil
+	default:
+		return nil, fmt.Errorf("method not supported: %s", method)
 	}
 }
+
+// generateRuleCommands renders every `rule` block currently in d into one
+// `modify group ... proto=... port=... action=...` command per port (or
+// port range), applying the rule's source/destination ranges when present.
+func generateRuleCommands(d *schema.ResourceData, groupName string, method string) []string {
+	rawRules, ok := d.GetOk("rule")
+	if !ok {
+		return nil
+	}
+
+	return renderRuleCommands(groupName, rawRules.(*schema.Set).List(), method)
+}
+
+// renderRuleCommands renders the given raw rule blocks (as produced by a
+// TypeSet's List()) into `modify group ... proto=... port=... action=...`
+// commands, one per port (or port range), applying source/destination
+// ranges when present.
+func renderRuleCommands(groupName string, rawRules []interface{}, method string) []string {
+	var cmds []string
+	for _, raw := range rawRules {
+		rule := raw.(map[string]interface{})
+
+		protocol := rule["protocol"].(string)
+		direction := rule["direction"].(string)
+		action := rule["action"].(string)
+		ports := sortPorts(toStringList(rule["ports"]))
+		sourceRanges := sortStrings(canonicalizeCIDRs(toStringList(rule["source_ranges"])))
+		destRanges := sortStrings(canonicalizeCIDRs(toStringList(rule["destination_ranges"])))
+
+		if len(ports) == 0 {
+			ports = []string{""}
+		}
+
+		for _, port := range ports {
+			cmd := fmt.Sprintf("modify group group=%s proto=%s direction=%s action=%s method=%s",
+				groupName, protocol, direction, action, method)
+			if port != "" {
+				cmd += fmt.Sprintf(" port=%s", port)
+			}
+			if len(sourceRanges) > 0 {
+				cmd += fmt.Sprintf(" source=%s", strings.Join(sourceRanges, ","))
+			}
+			if len(destRanges) > 0 {
+				cmd += fmt.Sprintf(" destination=%s", strings.Join(destRanges, ","))
+			}
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	return cmds
+}
@@ -0,0 +1,151 @@
+package fwautomation
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func testRule(protocol, direction, action string, ports []interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"protocol":           protocol,
+		"direction":          direction,
+		"action":             action,
+		"ports":              ports,
+		"source_ranges":      []interface{}{},
+		"destination_ranges": []interface{}{},
+	}
+}
+
+func TestRenderRuleCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    []interface{}
+		method   string
+		expected []string
+	}{
+		{
+			name:     "no rules",
+			rules:    nil,
+			method:   "add",
+			expected: nil,
+		},
+		{
+			name:   "single port rule",
+			rules:  []interface{}{testRule("tcp", "ingress", "allow", []interface{}{"443"})},
+			method: "add",
+			expected: []string{
+				"modify group group=web proto=tcp direction=ingress action=allow method=add port=443",
+			},
+		},
+		{
+			name:   "one command per port",
+			rules:  []interface{}{testRule("tcp", "ingress", "allow", []interface{}{"443", "80"})},
+			method: "remove",
+			expected: []string{
+				"modify group group=web proto=tcp direction=ingress action=allow method=remove port=80",
+				"modify group group=web proto=tcp direction=ingress action=allow method=remove port=443",
+			},
+		},
+		{
+			name:   "rule with no ports omits the port field",
+			rules:  []interface{}{testRule("icmp", "ingress", "deny", nil)},
+			method: "add",
+			expected: []string{
+				"modify group group=web proto=icmp direction=ingress action=deny method=add",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderRuleCommands("web", tt.rules, tt.method)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("renderRuleCommands() = %#v, want %#v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUpdateFirewallGroupRulesDiff(t *testing.T) {
+	httpsRule := testRule("tcp", "ingress", "allow", []interface{}{"443"})
+	sshRule := testRule("tcp", "ingress", "allow", []interface{}{"22"})
+	httpRule := testRule("tcp", "ingress", "allow", []interface{}{"80"})
+
+	tests := []struct {
+		name        string
+		oldRules    []interface{}
+		newRules    []interface{}
+		wantRemoved []interface{}
+		wantAdded   []interface{}
+	}{
+		{
+			name:        "unchanged rules produce no diff",
+			oldRules:    []interface{}{httpsRule},
+			newRules:    []interface{}{httpsRule},
+			wantRemoved: nil,
+			wantAdded:   nil,
+		},
+		{
+			name:        "added rule",
+			oldRules:    []interface{}{httpsRule},
+			newRules:    []interface{}{httpsRule, sshRule},
+			wantRemoved: nil,
+			wantAdded:   []interface{}{sshRule},
+		},
+		{
+			name:        "removed rule",
+			oldRules:    []interface{}{httpsRule, sshRule},
+			newRules:    []interface{}{httpsRule},
+			wantRemoved: []interface{}{sshRule},
+			wantAdded:   nil,
+		},
+		{
+			name:        "one rule swapped for another",
+			oldRules:    []interface{}{httpsRule},
+			newRules:    []interface{}{httpRule},
+			wantRemoved: []interface{}{httpsRule},
+			wantAdded:   []interface{}{httpRule},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldSet := schema.NewSet(resourceFirewallRuleHash, tt.oldRules)
+			newSet := schema.NewSet(resourceFirewallRuleHash, tt.newRules)
+
+			removed := oldSet.Difference(newSet).List()
+			added := newSet.Difference(oldSet).List()
+
+			if !sameRuleSet(removed, tt.wantRemoved) {
+				t.Errorf("removed = %#v, want %#v", removed, tt.wantRemoved)
+			}
+			if !sameRuleSet(added, tt.wantAdded) {
+				t.Errorf("added = %#v, want %#v", added, tt.wantAdded)
+			}
+		})
+	}
+}
+
+// sameRuleSet compares two rule lists regardless of order, since
+// schema.Set.List() does not guarantee one.
+func sameRuleSet(got, want []interface{}) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	gotCmds := make([]string, len(got))
+	for i, r := range got {
+		gotCmds[i] = renderRuleCommands("web", []interface{}{r}, "add")[0]
+	}
+	wantCmds := make([]string, len(want))
+	for i, r := range want {
+		wantCmds[i] = renderRuleCommands("web", []interface{}{r}, "add")[0]
+	}
+
+	sort.Strings(gotCmds)
+	sort.Strings(wantCmds)
+	return reflect.DeepEqual(gotCmds, wantCmds)
+}
@@ -0,0 +1,175 @@
+package fwautomation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// dialManagementServer opens a new SSH connection to the management
+// server, using whichever auth and host key verification mode the
+// provider is configured for. The dial is aborted, and any connection it
+// raced to completion is closed, if ctx is done first.
+func dialManagementServer(ctx context.Context, config *ManagementConfig) (*ssh.Client, error) {
+	auth, err := buildSSHAuthMethods(config)
+	if err != nil {
+		return nil, err
+	}
+
+	hostKeyCallback, err := buildHostKeyCallback(config)
+	if err != nil {
+		return nil, err
+	}
+
+	sshConfig := &ssh.ClientConfig{
+		User:            config.SSHUser,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         5 * time.Second,
+	}
+	if len(config.HostKeyAlgorithms) > 0 {
+		sshConfig.HostKeyAlgorithms = config.HostKeyAlgorithms
+	}
+
+	// Ensure the server address includes a port
+	serverAddress := config.Server
+	if !strings.Contains(serverAddress, ":") {
+		serverAddress = fmt.Sprintf("%s:22", serverAddress) // Append port if not present
+	}
+
+	type dialResult struct {
+		client *ssh.Client
+		err    error
+	}
+	resultCh := make(chan dialResult, 1)
+	go func() {
+		client, err := ssh.Dial("tcp", serverAddress, sshConfig)
+		resultCh <- dialResult{client, err}
+	}()
+
+	select {
+	case result := <-resultCh:
+		return result.client, result.err
+	case <-ctx.Done():
+		go func() {
+			if result := <-resultCh; result.client != nil {
+				result.client.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// buildSSHAuthMethods assembles the configured auth methods in order of
+// preference: an ssh-agent when enabled, the private key file (optionally
+// passphrase-protected), and finally a password.
+func buildSSHAuthMethods(config *ManagementConfig) ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+
+	if config.SSHAgent {
+		signers, err := sshAgentSigners()
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to ssh-agent: %s", err)
+		}
+		methods = append(methods, ssh.PublicKeysCallback(signers))
+	}
+
+	if config.AuthenticationKeyPath != "" {
+		signer, err := loadPrivateKeySigner(config.AuthenticationKeyPath, config.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+
+	if config.Password != "" {
+		methods = append(methods, ssh.Password(config.Password))
+	}
+
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("no SSH auth method configured: set ssh_agent, authentication_key_path, or password")
+	}
+
+	return methods, nil
+}
+
+func sshAgentSigners() (func() ([]ssh.Signer, error), error) {
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil, fmt.Errorf("SSH_AUTH_SOCK is not set")
+	}
+
+	conn, err := net.Dial("unix", sock)
+	if err != nil {
+		return nil, err
+	}
+
+	return agent.NewClient(conn).Signers, nil
+}
+
+func loadPrivateKeySigner(path string, passphrase string) (ssh.Signer, error) {
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load private key: %s", err)
+	}
+
+	if passphrase != "" {
+		signer, err := ssh.ParsePrivateKeyWithPassphrase(key, []byte(passphrase))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %s", err)
+		}
+		return signer, nil
+	}
+
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %s", err)
+	}
+	return signer, nil
+}
+
+// buildHostKeyCallback picks the strictest host key verification mode the
+// provider is configured for: a pinned key when `host_key_fingerprint` is
+// set, otherwise the `known_hosts_path` file, otherwise
+// ssh.InsecureIgnoreHostKey when the caller explicitly opted in for lab
+// use. With none of these set it returns an error.
+func buildHostKeyCallback(config *ManagementConfig) (ssh.HostKeyCallback, error) {
+	if config.HostKeyFingerprint != "" {
+		pinned, err := parseHostKeyFingerprint(config.HostKeyFingerprint)
+		if err != nil {
+			return nil, err
+		}
+		return ssh.FixedHostKey(pinned), nil
+	}
+
+	if config.KnownHostsPath != "" {
+		callback, err := knownhosts.New(config.KnownHostsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load known_hosts file %q: %s", config.KnownHostsPath, err)
+		}
+		return callback, nil
+	}
+
+	if config.InsecureIgnoreHostKey {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+
+	return nil, fmt.Errorf("no host key verification configured: set known_hosts_path, host_key_fingerprint, or insecure_ignore_host_key")
+}
+
+// parseHostKeyFingerprint parses an authorized_keys-formatted public key
+// (e.g. "ssh-ed25519 AAAA...") pinned via host_key_fingerprint.
+func parseHostKeyFingerprint(fingerprint string) (ssh.PublicKey, error) {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(fingerprint))
+	if err != nil {
+		return nil, fmt.Errorf("invalid host_key_fingerprint: %s", err)
+	}
+	return key, nil
+}
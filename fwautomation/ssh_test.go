@@ -0,0 +1,176 @@
+package fwautomation
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func writeTestRSAKey(t *testing.T) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate test key: %s", err)
+	}
+
+	block := &pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}
+
+	path := filepath.Join(t.TempDir(), "id_rsa")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatalf("failed to write test key: %s", err)
+	}
+
+	return path
+}
+
+func testHostPublicKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate test host key: %s", err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatalf("failed to convert test host key: %s", err)
+	}
+
+	return sshPub
+}
+
+func TestBuildSSHAuthMethods(t *testing.T) {
+	keyPath := writeTestRSAKey(t)
+
+	t.Run("no auth method configured", func(t *testing.T) {
+		_, err := buildSSHAuthMethods(&ManagementConfig{})
+		if err == nil {
+			t.Fatal("expected an error when no auth method is configured")
+		}
+	})
+
+	t.Run("password only", func(t *testing.T) {
+		methods, err := buildSSHAuthMethods(&ManagementConfig{Password: "hunter2"})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(methods) != 1 {
+			t.Fatalf("expected 1 auth method, got %d", len(methods))
+		}
+	})
+
+	t.Run("private key only", func(t *testing.T) {
+		methods, err := buildSSHAuthMethods(&ManagementConfig{AuthenticationKeyPath: keyPath})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(methods) != 1 {
+			t.Fatalf("expected 1 auth method, got %d", len(methods))
+		}
+	})
+
+	t.Run("private key and password combine", func(t *testing.T) {
+		methods, err := buildSSHAuthMethods(&ManagementConfig{AuthenticationKeyPath: keyPath, Password: "hunter2"})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(methods) != 2 {
+			t.Fatalf("expected 2 auth methods, got %d", len(methods))
+		}
+	})
+
+	t.Run("ssh agent enabled but unreachable", func(t *testing.T) {
+		t.Setenv("SSH_AUTH_SOCK", "")
+		_, err := buildSSHAuthMethods(&ManagementConfig{SSHAgent: true})
+		if err == nil {
+			t.Fatal("expected an error when ssh_agent is enabled but SSH_AUTH_SOCK is unset")
+		}
+	})
+
+	t.Run("missing private key file", func(t *testing.T) {
+		_, err := buildSSHAuthMethods(&ManagementConfig{AuthenticationKeyPath: filepath.Join(t.TempDir(), "missing")})
+		if err == nil {
+			t.Fatal("expected an error for a missing private key file")
+		}
+	})
+}
+
+func TestBuildHostKeyCallback(t *testing.T) {
+	hostKey := testHostPublicKey(t)
+	fingerprint := string(ssh.MarshalAuthorizedKey(hostKey))
+
+	t.Run("no host key verification configured", func(t *testing.T) {
+		_, err := buildHostKeyCallback(&ManagementConfig{})
+		if err == nil {
+			t.Fatal("expected an error when no host key verification mode is configured")
+		}
+	})
+
+	t.Run("insecure_ignore_host_key opt-in", func(t *testing.T) {
+		callback, err := buildHostKeyCallback(&ManagementConfig{InsecureIgnoreHostKey: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if callback == nil {
+			t.Fatal("expected a non-nil callback")
+		}
+	})
+
+	t.Run("pinned host_key_fingerprint", func(t *testing.T) {
+		callback, err := buildHostKeyCallback(&ManagementConfig{HostKeyFingerprint: fingerprint})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if err := callback("example.com:22", nil, hostKey); err != nil {
+			t.Errorf("expected the pinned key to be accepted, got: %s", err)
+		}
+
+		otherKey := testHostPublicKey(t)
+		if err := callback("example.com:22", nil, otherKey); err == nil {
+			t.Error("expected a different key to be rejected")
+		}
+	})
+
+	t.Run("invalid host_key_fingerprint", func(t *testing.T) {
+		_, err := buildHostKeyCallback(&ManagementConfig{HostKeyFingerprint: "not a key"})
+		if err == nil {
+			t.Fatal("expected an error for an invalid host_key_fingerprint")
+		}
+	})
+
+	t.Run("known_hosts_path", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "known_hosts")
+		line := "example.com " + fingerprint
+		if err := os.WriteFile(path, []byte(line), 0o600); err != nil {
+			t.Fatalf("failed to write known_hosts file: %s", err)
+		}
+
+		callback, err := buildHostKeyCallback(&ManagementConfig{KnownHostsPath: path})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		addr := &net.TCPAddr{IP: net.ParseIP("93.184.216.34"), Port: 22}
+		if err := callback("example.com:22", addr, hostKey); err != nil {
+			t.Errorf("expected the known host's key to be accepted, got: %s", err)
+		}
+	})
+
+	t.Run("missing known_hosts_path", func(t *testing.T) {
+		_, err := buildHostKeyCallback(&ManagementConfig{KnownHostsPath: filepath.Join(t.TempDir(), "missing")})
+		if err == nil {
+			t.Fatal("expected an error for a missing known_hosts file")
+		}
+	})
+}
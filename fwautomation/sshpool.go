@@ -0,0 +1,138 @@
+package fwautomation
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// maxConcurrentSSHSessions bounds how many SSH sessions the pool will have
+// open on its shared connection at once, so a large plan fans out without
+// overwhelming the management server.
+const maxConcurrentSSHSessions = 4
+
+// sshClientPool lazily dials a single multiplexed SSH connection to the
+// management server and hands out sessions on it, guarded by a semaphore,
+// so a plan touching many resources shares one handshake instead of
+// opening a new connection per CRUD call. If the connection drops, the
+// next session request transparently redials.
+type sshClientPool struct {
+	dial func(ctx context.Context) (*ssh.Client, error)
+
+	mu     sync.Mutex
+	client *ssh.Client
+
+	sem chan struct{}
+}
+
+func newSSHClientPool(dial func(ctx context.Context) (*ssh.Client, error)) *sshClientPool {
+	return &sshClientPool{
+		dial: dial,
+		sem:  make(chan struct{}, maxConcurrentSSHSessions),
+	}
+}
+
+// connect returns the pool's shared *ssh.Client, dialing it on first use
+// (or redialing after a previous connection was detected as broken). It
+// aborts the dial if ctx is done first.
+func (p *sshClientPool) connect(ctx context.Context) (*ssh.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil {
+		return p.client, nil
+	}
+
+	client, err := p.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.client = client
+	go p.watch(client)
+
+	return client, nil
+}
+
+// watch blocks until the connection drops, then clears it so the next
+// caller redials rather than handing out sessions on a dead client.
+func (p *sshClientPool) watch(client *ssh.Client) {
+	client.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.client == client {
+		p.client = nil
+	}
+}
+
+// pooledSession wraps an *ssh.Session so that Close releases the pool's
+// concurrency slot and stops the context-cancellation watcher, instead of
+// tearing down the shared connection.
+type pooledSession struct {
+	*ssh.Session
+	release   func()
+	stopWatch func()
+}
+
+func (s *pooledSession) Close() error {
+	s.stopWatch()
+	err := s.Session.Close()
+	s.release()
+	return err
+}
+
+// NewSession blocks until a concurrency slot is free, then returns a new
+// session on the pool's shared connection. If ctx is cancelled while a
+// command is running on the session, the session is sent SIGKILL and
+// closed. The caller must Close the session when done; doing so only
+// releases the slot, it does not close the underlying connection.
+func (p *sshClientPool) NewSession(ctx context.Context) (*pooledSession, error) {
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	client, err := p.connect(ctx)
+	if err != nil {
+		<-p.sem
+		return nil, err
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		<-p.sem
+		return nil, err
+	}
+
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			session.Signal(ssh.SIGKILL)
+			session.Close()
+		case <-stop:
+		}
+	}()
+
+	return &pooledSession{
+		Session:   session,
+		release:   func() { <-p.sem },
+		stopWatch: func() { close(stop) },
+	}, nil
+}
+
+// Close tears down the pool's shared connection, if one is open.
+func (p *sshClientPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client == nil {
+		return nil
+	}
+	err := p.client.Close()
+	p.client = nil
+	return err
+}